@@ -0,0 +1,564 @@
+package vcsclient
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gfleury/go-bitbucket-v1"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"golang.org/x/oauth2"
+)
+
+const (
+	bitbucketServerEventHeader     = "X-Event-Key"
+	bitbucketServerSignatureHeader = "X-Hub-Signature"
+)
+
+var bitbucketServerOAuthScopes = []string{"PROJECT_READ", "REPOSITORY_READ", "REPOSITORY_WRITE"}
+
+type BitbucketServerClient struct {
+	vcsInfo VcsInfo
+}
+
+func NewBitbucketServerClient(vcsInfo VcsInfo) (*BitbucketServerClient, error) {
+	return &BitbucketServerClient{vcsInfo: vcsInfo}, nil
+}
+
+func (client *BitbucketServerClient) buildBitbucketClient(ctx context.Context) *bitbucketv1.APIClient {
+	basicAuth := bitbucketv1.BasicAuth{UserName: client.vcsInfo.Username, Password: client.vcsInfo.Token}
+	ctx = context.WithValue(ctx, bitbucketv1.ContextBasicAuth, basicAuth)
+	return bitbucketv1.NewAPIClient(ctx, bitbucketv1.NewConfiguration(client.vcsInfo.ApiEndpoint))
+}
+
+func (client *BitbucketServerClient) TestConnection(ctx context.Context) error {
+	bbClient := client.buildBitbucketClient(ctx)
+	_, _, err := bbClient.DefaultApi.GetUsers(nil)
+	return err
+}
+
+func (client *BitbucketServerClient) AddSshKeyToRepository(ctx context.Context, owner, repository, keyName,
+	publicKey string, permission Permission) error {
+	err := validateParametersNotBlank(map[string]string{
+		"owner":      owner,
+		"repository": repository,
+		"key name":   keyName,
+		"public key": publicKey,
+	})
+	if err != nil {
+		return err
+	}
+	bbClient := client.buildBitbucketClient(ctx)
+	permissionValue := "REPO_READ"
+	if permission == ReadWrite {
+		permissionValue = "REPO_WRITE"
+	}
+	_, err = bbClient.DefaultApi.AddSshKey(owner, repository, bitbucketv1.SshKeyRequest{
+		Text:       publicKey,
+		Label:      keyName,
+		Permission: permissionValue,
+	})
+	return err
+}
+
+func (client *BitbucketServerClient) ListRepositories(ctx context.Context) (map[string][]string, error) {
+	bbClient := client.buildBitbucketClient(ctx)
+	results := make(map[string][]string)
+	for page := 0; ; page += 25 {
+		response, err := bbClient.DefaultApi.GetRepositories_19(map[string]interface{}{"start": page, "limit": 25})
+		if err != nil {
+			return nil, err
+		}
+		repos, err := bitbucketv1.GetRepositoriesResponse(response)
+		if err != nil {
+			return nil, err
+		}
+		for _, repo := range repos {
+			results[repo.Project.Key] = append(results[repo.Project.Key], repo.Slug)
+		}
+		if bitbucketv1.GetIsLastPage(response) {
+			break
+		}
+	}
+	return results, nil
+}
+
+func (client *BitbucketServerClient) ListBranches(ctx context.Context, owner, repository string) ([]string, error) {
+	bbClient := client.buildBitbucketClient(ctx)
+	response, err := bbClient.DefaultApi.GetBranches(owner, repository, nil)
+	if err != nil {
+		return nil, err
+	}
+	branches, err := bitbucketv1.GetBranchesResponse(response)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]string, 0, len(branches))
+	for _, branch := range branches {
+		results = append(results, branch.DisplayID)
+	}
+	return results, nil
+}
+
+func (client *BitbucketServerClient) CreateWebhook(ctx context.Context, owner, repository, _, payloadUrl string,
+	webhookEvents ...vcsutils.WebhookEvent) (string, string, error) {
+	bbClient := client.buildBitbucketClient(ctx)
+	token := vcsutils.CreateToken()
+	response, err := bbClient.DefaultApi.CreateWebhook(owner, repository,
+		createBitbucketServerHookOptions(token, payloadUrl, webhookEvents...), []string{"application/json"})
+	if err != nil {
+		return "", "", err
+	}
+	hook, err := bitbucketv1.GetWebhookResponse(response)
+	if err != nil {
+		return "", "", err
+	}
+	return strconv.Itoa(hook.ID), token, nil
+}
+
+func (client *BitbucketServerClient) UpdateWebhook(ctx context.Context, owner, repository, _, payloadUrl, token,
+	webhookId string, webhookEvents ...vcsutils.WebhookEvent) error {
+	bbClient := client.buildBitbucketClient(ctx)
+	webhookIdInt, err := strconv.Atoi(webhookId)
+	if err != nil {
+		return err
+	}
+	_, err = bbClient.DefaultApi.UpdateWebhook(owner, repository, webhookIdInt,
+		createBitbucketServerHookOptions(token, payloadUrl, webhookEvents...), []string{"application/json"})
+	return err
+}
+
+func (client *BitbucketServerClient) DeleteWebhook(ctx context.Context, owner, repository, webhookId string) error {
+	bbClient := client.buildBitbucketClient(ctx)
+	webhookIdInt, err := strconv.Atoi(webhookId)
+	if err != nil {
+		return err
+	}
+	_, err = bbClient.DefaultApi.DeleteWebhook(owner, repository, webhookIdInt)
+	return err
+}
+
+func (client *BitbucketServerClient) SetCommitStatus(ctx context.Context, commitStatus CommitStatus, owner,
+	repository, ref, title, description, detailsUrl string) error {
+	bbClient := client.buildBitbucketClient(ctx)
+	_, err := bbClient.DefaultApi.SetCommitStatus(ref, map[string]interface{}{
+		"state":       getBitbucketServerCommitState(commitStatus),
+		"key":         title,
+		"description": description,
+		"url":         detailsUrl,
+	})
+	return err
+}
+
+func (client *BitbucketServerClient) DownloadRepository(ctx context.Context, owner, repository, branch,
+	localPath string) error {
+	bbClient := client.buildBitbucketClient(ctx)
+	response, err := bbClient.DefaultApi.StreamArchive_26(owner, repository,
+		map[string]interface{}{"at": branch, "format": "tar.gz"})
+	if err != nil {
+		return err
+	}
+	return vcsutils.Untar(localPath, strings.NewReader(string(response.Payload)), true)
+}
+
+func (client *BitbucketServerClient) CreatePullRequest(ctx context.Context, owner, repository, sourceBranch,
+	targetBranch, title, description string) error {
+	bbClient := client.buildBitbucketClient(ctx)
+	_, err := bbClient.DefaultApi.CreatePullRequest(owner, repository, bitbucketv1.PullRequest{
+		Title:       title,
+		Description: description,
+		FromRef: bitbucketv1.PullRequestRef{
+			ID:         "refs/heads/" + sourceBranch,
+			Repository: bitbucketv1.Repository{Slug: repository, Project: &bitbucketv1.Project{Key: owner}},
+		},
+		ToRef: bitbucketv1.PullRequestRef{
+			ID:         "refs/heads/" + targetBranch,
+			Repository: bitbucketv1.Repository{Slug: repository, Project: &bitbucketv1.Project{Key: owner}},
+		},
+	})
+	return err
+}
+
+func (client *BitbucketServerClient) GetLatestCommit(ctx context.Context, owner, repository,
+	branch string) (CommitInfo, error) {
+	err := validateParametersNotBlank(map[string]string{"owner": owner, "repository": repository, "branch": branch})
+	if err != nil {
+		return CommitInfo{}, err
+	}
+	bbClient := client.buildBitbucketClient(ctx)
+	response, err := bbClient.DefaultApi.GetCommits(owner, repository,
+		map[string]interface{}{"until": branch, "limit": 1})
+	if err != nil {
+		return CommitInfo{}, err
+	}
+	commits, err := bitbucketv1.GetCommitsResponse(response)
+	if err != nil {
+		return CommitInfo{}, err
+	}
+	if len(commits) > 0 {
+		commitInfo := mapBitbucketServerCommitToCommitInfo(commits[0])
+		commitInfo.Url = client.commitUrl(owner, repository, commitInfo.Hash)
+		return commitInfo, nil
+	}
+	return CommitInfo{}, nil
+}
+
+func (client *BitbucketServerClient) GetRepositoryInfo(ctx context.Context, owner,
+	repository string) (RepositoryInfo, error) {
+	err := validateParametersNotBlank(map[string]string{"owner": owner, "repository": repository})
+	if err != nil {
+		return RepositoryInfo{}, err
+	}
+	bbClient := client.buildBitbucketClient(ctx)
+	response, err := bbClient.DefaultApi.GetRepository(owner, repository)
+	if err != nil {
+		return RepositoryInfo{}, err
+	}
+	repo, err := bitbucketv1.GetRepositoryResponse(response)
+	if err != nil {
+		return RepositoryInfo{}, err
+	}
+	var httpUrl, sshUrl string
+	for _, clone := range repo.Links.Clone {
+		switch clone.Name {
+		case "http":
+			httpUrl = clone.Href
+		case "ssh":
+			sshUrl = clone.Href
+		}
+	}
+	return RepositoryInfo{CloneInfo: CloneInfo{
+		HTTP:  httpUrl,
+		SSH:   sshUrl,
+		Token: client.vcsInfo.Token,
+	}}, nil
+}
+
+func (client *BitbucketServerClient) GetCommitBySha(ctx context.Context, owner, repository,
+	sha string) (CommitInfo, error) {
+	err := validateParametersNotBlank(map[string]string{"owner": owner, "repository": repository, "sha": sha})
+	if err != nil {
+		return CommitInfo{}, err
+	}
+	bbClient := client.buildBitbucketClient(ctx)
+	response, err := bbClient.DefaultApi.GetCommit(owner, repository, sha, nil)
+	if err != nil {
+		return CommitInfo{}, err
+	}
+	commit, err := bitbucketv1.GetCommitResponse(response)
+	if err != nil {
+		return CommitInfo{}, err
+	}
+	commitInfo := mapBitbucketServerCommitToCommitInfo(commit)
+	commitInfo.Url = client.commitUrl(owner, repository, commitInfo.Hash)
+	return commitInfo, nil
+}
+
+func (client *BitbucketServerClient) AddPullRequestComment(ctx context.Context, owner, repository string, prID int,
+	content string) error {
+	bbClient := client.buildBitbucketClient(ctx)
+	_, err := bbClient.DefaultApi.CreatePullRequestComment(owner, repository, prID,
+		map[string]interface{}{"text": content}, []string{"application/json"})
+	return err
+}
+
+func (client *BitbucketServerClient) ListPullRequestComments(ctx context.Context, owner, repository string,
+	prID int) ([]CommentInfo, error) {
+	bbClient := client.buildBitbucketClient(ctx)
+	response, err := bbClient.DefaultApi.GetPullRequestComments(owner, repository, prID, nil)
+	if err != nil {
+		return nil, err
+	}
+	activities, err := bitbucketv1.GetActivitiesResponse(response)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]CommentInfo, 0, len(activities))
+	for _, activity := range activities {
+		if activity.Comment == nil {
+			continue
+		}
+		results = append(results, mapBitbucketServerCommentToCommentInfo(*activity.Comment))
+	}
+	return results, nil
+}
+
+func (client *BitbucketServerClient) EditPullRequestComment(ctx context.Context, owner, repository string,
+	commentID int64, content string) error {
+	bbClient := client.buildBitbucketClient(ctx)
+	_, err := bbClient.DefaultApi.UpdatePullRequestComment(owner, repository, int(commentID),
+		map[string]interface{}{"text": content}, []string{"application/json"})
+	return err
+}
+
+func (client *BitbucketServerClient) DeletePullRequestComment(ctx context.Context, owner, repository string,
+	commentID int64) error {
+	bbClient := client.buildBitbucketClient(ctx)
+	_, err := bbClient.DefaultApi.DeletePullRequestComment(owner, repository, int(commentID), nil)
+	return err
+}
+
+func mapBitbucketServerCommentToCommentInfo(comment bitbucketv1.Comment) CommentInfo {
+	return CommentInfo{
+		ID:      int64(comment.ID),
+		Content: comment.Text,
+		Created: comment.CreatedDate / 1000,
+		Author:  comment.Author.Name,
+	}
+}
+
+func (client *BitbucketServerClient) GetFileContent(ctx context.Context, owner, repository, ref, path string) ([]byte, error) {
+	err := validateParametersNotBlank(map[string]string{"owner": owner, "repository": repository, "path": path})
+	if err != nil {
+		return nil, err
+	}
+	bbClient := client.buildBitbucketClient(ctx)
+	response, err := bbClient.DefaultApi.GetContent_11(owner, repository, path, map[string]interface{}{"at": ref})
+	if err != nil {
+		return nil, err
+	}
+	return response.Payload, nil
+}
+
+func (client *BitbucketServerClient) ListDirectory(ctx context.Context, owner, repository, ref,
+	path string) ([]FileEntry, error) {
+	err := validateParametersNotBlank(map[string]string{"owner": owner, "repository": repository})
+	if err != nil {
+		return nil, err
+	}
+	bbClient := client.buildBitbucketClient(ctx)
+	response, err := bbClient.DefaultApi.StreamFiles_22(owner, repository, path, map[string]interface{}{"at": ref})
+	if err != nil {
+		return nil, err
+	}
+	paths, err := bitbucketv1.GetFilesResponse(response)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]FileEntry, len(paths))
+	for i, entryPath := range paths {
+		results[i] = FileEntry{Name: entryPath[strings.LastIndex(entryPath, "/")+1:], Path: entryPath, Type: "blob"}
+	}
+	return results, nil
+}
+
+func (client *BitbucketServerClient) bitbucketServerOAuthConfig() *oauth2.Config {
+	baseUrl := strings.TrimSuffix(client.vcsInfo.ApiEndpoint, "/")
+	return &oauth2.Config{
+		ClientID:     client.vcsInfo.OAuth2ClientID,
+		ClientSecret: client.vcsInfo.OAuth2Secret,
+		RedirectURL:  client.vcsInfo.OAuth2RedirectURL,
+		Scopes:       bitbucketServerOAuthScopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  baseUrl + "/rest/oauth2/latest/authorize",
+			TokenURL: baseUrl + "/rest/oauth2/latest/token",
+		},
+	}
+}
+
+func (client *BitbucketServerClient) Login(ctx context.Context, w http.ResponseWriter, r *http.Request) (string, error) {
+	state, err := generateOAuthState()
+	if err != nil {
+		return "", err
+	}
+	setOAuthStateCookie(w, state)
+	http.Redirect(w, r, client.bitbucketServerOAuthConfig().AuthCodeURL(state), http.StatusFound)
+	return state, nil
+}
+
+func (client *BitbucketServerClient) Authenticate(ctx context.Context, w http.ResponseWriter, r *http.Request,
+	state string) (UserInfo, error) {
+	if err := validateOAuthState(r, state); err != nil {
+		return UserInfo{}, err
+	}
+	config := client.bitbucketServerOAuthConfig()
+	token, err := config.Exchange(ctx, r.URL.Query().Get("code"))
+	if err != nil {
+		return UserInfo{}, err
+	}
+	resp, err := config.Client(ctx, token).Get(strings.TrimSuffix(client.vcsInfo.ApiEndpoint, "/") + "/plugins/servlet/applinks/whoami")
+	if err != nil {
+		return UserInfo{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var user struct {
+		Name         string `json:"name"`
+		EmailAddress string `json:"emailAddress"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return UserInfo{}, err
+	}
+	return UserInfo{Login: user.Name, Email: user.EmailAddress, Token: token.AccessToken}, nil
+}
+
+func (client *BitbucketServerClient) GetRepositoryPermissions(ctx context.Context, owner, repository,
+	username string) (RepoPermissions, error) {
+	err := validateParametersNotBlank(map[string]string{"owner": owner, "repository": repository, "username": username})
+	if err != nil {
+		return RepoPermissions{}, err
+	}
+	bbClient := client.buildBitbucketClient(ctx)
+	response, err := bbClient.DefaultApi.GetUsersWithAnyPermission(owner, repository,
+		map[string]interface{}{"filter": username})
+	if err != nil {
+		return RepoPermissions{}, err
+	}
+	permissions, err := bitbucketv1.GetUserPermissionsResponse(response)
+	if err != nil {
+		return RepoPermissions{}, err
+	}
+	for _, permission := range permissions {
+		if permission.User.Name != username {
+			continue
+		}
+		switch permission.Permission {
+		case "REPO_ADMIN":
+			return RepoPermissions{Admin: true, Push: true, Pull: true}, nil
+		case "REPO_WRITE":
+			return RepoPermissions{Push: true, Pull: true}, nil
+		case "REPO_READ":
+			return RepoPermissions{Pull: true}, nil
+		}
+	}
+	return RepoPermissions{}, nil
+}
+
+func (client *BitbucketServerClient) ParseIncomingWebhook(ctx context.Context, headers http.Header, body []byte,
+	secretToken string) (*WebhookInfo, error) {
+	if err := verifyBitbucketServerWebhookSignature(headers.Get(bitbucketServerSignatureHeader), body,
+		secretToken); err != nil {
+		return nil, err
+	}
+	switch headers.Get(bitbucketServerEventHeader) {
+	case "repo:refs_changed":
+		return parseBitbucketServerPushWebhook(body)
+	case "pr:opened", "pr:from_ref_updated":
+		return parseBitbucketServerPullRequestWebhook(headers.Get(bitbucketServerEventHeader), body)
+	default:
+		return nil, fmt.Errorf("unsupported bitbucket server webhook event: %s", headers.Get(bitbucketServerEventHeader))
+	}
+}
+
+func verifyBitbucketServerWebhookSignature(signatureHeader string, body []byte, secretToken string) error {
+	expectedSignature := strings.TrimPrefix(signatureHeader, "sha256=")
+	mac := hmac.New(sha256.New, []byte(secretToken))
+	mac.Write(body)
+	computedSignature := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expectedSignature), []byte(computedSignature)) {
+		return &WebhookSignatureMismatchError{}
+	}
+	return nil
+}
+
+func parseBitbucketServerPushWebhook(body []byte) (*WebhookInfo, error) {
+	var payload struct {
+		Repository bitbucketv1.Repository `json:"repository"`
+		Changes    []struct {
+			RefID  string `json:"refId"`
+			ToHash string `json:"toHash"`
+		} `json:"changes"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	var branch string
+	var commits []WebhookInfoCommit
+	if len(payload.Changes) > 0 {
+		branch = strings.TrimPrefix(payload.Changes[0].RefID, "refs/heads/")
+		commits = []WebhookInfoCommit{{Hash: payload.Changes[0].ToHash}}
+	}
+	return &WebhookInfo{
+		TargetRepositoryOwner: payload.Repository.Project.Key,
+		TargetRepositoryName:  payload.Repository.Slug,
+		Event:                 vcsutils.Push,
+		Branch:                branch,
+		Commits:               commits,
+	}, nil
+}
+
+func parseBitbucketServerPullRequestWebhook(eventKey string, body []byte) (*WebhookInfo, error) {
+	var payload struct {
+		PullRequest bitbucketv1.PullRequest `json:"pullRequest"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	event := vcsutils.PrEdited
+	if eventKey == "pr:opened" {
+		event = vcsutils.PrCreated
+	}
+	return &WebhookInfo{
+		TargetRepositoryOwner: payload.PullRequest.ToRef.Repository.Project.Key,
+		TargetRepositoryName:  payload.PullRequest.ToRef.Repository.Slug,
+		Event:                 event,
+		SourceBranch:          strings.TrimPrefix(payload.PullRequest.FromRef.ID, "refs/heads/"),
+		TargetBranch:          strings.TrimPrefix(payload.PullRequest.ToRef.ID, "refs/heads/"),
+		PullRequestId:         payload.PullRequest.ID,
+		PullRequestTitle:      payload.PullRequest.Title,
+		PullRequestBody:       payload.PullRequest.Description,
+	}, nil
+}
+
+func createBitbucketServerHookOptions(token, payloadUrl string,
+	webhookEvents ...vcsutils.WebhookEvent) bitbucketv1.Webhook {
+	return bitbucketv1.Webhook{
+		Url:           payloadUrl,
+		Active:        true,
+		Events:        getBitbucketServerWebhookEvents(webhookEvents...),
+		Configuration: bitbucketv1.WebhookConfiguration{Secret: token},
+	}
+}
+
+// Get varargs of webhook events and return a slice of Bitbucket Server webhook events
+func getBitbucketServerWebhookEvents(webhookEvents ...vcsutils.WebhookEvent) []string {
+	events := make([]string, 0, len(webhookEvents))
+	for _, event := range webhookEvents {
+		switch event {
+		case vcsutils.PrCreated:
+			events = append(events, "pr:opened")
+		case vcsutils.PrEdited:
+			events = append(events, "pr:from_ref_updated")
+		case vcsutils.Push:
+			events = append(events, "repo:refs_changed")
+		}
+	}
+	return events
+}
+
+func getBitbucketServerCommitState(commitState CommitStatus) string {
+	switch commitState {
+	case Pass:
+		return "SUCCESSFUL"
+	case Fail, Error:
+		return "FAILED"
+	case InProgress:
+		return "INPROGRESS"
+	}
+	return ""
+}
+
+func (client *BitbucketServerClient) commitUrl(owner, repository, sha string) string {
+	return fmt.Sprintf("%s/projects/%s/repos/%s/commits/%s", strings.TrimSuffix(client.vcsInfo.ApiEndpoint, "/"),
+		owner, repository, sha)
+}
+
+func mapBitbucketServerCommitToCommitInfo(commit bitbucketv1.Commit) CommitInfo {
+	parents := make([]string, len(commit.Parents))
+	for i, c := range commit.Parents {
+		parents[i] = c.ID
+	}
+	return CommitInfo{
+		Hash:          commit.ID,
+		AuthorName:    commit.Author.Name,
+		CommitterName: commit.Committer.Name,
+		Timestamp:     commit.AuthorTimestamp / 1000,
+		Message:       commit.Message,
+		ParentHashes:  parents,
+	}
+}
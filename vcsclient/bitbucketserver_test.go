@@ -0,0 +1,74 @@
+package vcsclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func createBitbucketServerClient(t *testing.T, handler http.HandlerFunc) (*BitbucketServerClient, func()) {
+	server := httptest.NewServer(handler)
+	client, err := NewBitbucketServerClient(VcsInfo{ApiEndpoint: server.URL, Token: token})
+	assert.NoError(t, err)
+	return client, server.Close
+}
+
+func TestBitbucketServer_BuiltThroughClientBuilder(t *testing.T) {
+	client, err := NewClientBuilder(vcsutils.BitbucketServer).ApiEndpoint("https://bitbucket.example.com").
+		Token(token).Build()
+	assert.NoError(t, err)
+	assert.IsType(t, &BitbucketServerClient{}, client)
+}
+
+func TestBitbucketServer_GetFileContent(t *testing.T) {
+	expectedContent := []byte("content")
+	client, cleanup := createBitbucketServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Contains(t, r.RequestURI, "README.md")
+		_, _ = w.Write(expectedContent)
+	})
+	defer cleanup()
+
+	content, err := client.GetFileContent(context.Background(), owner, repo1, branch1, "README.md")
+	assert.NoError(t, err)
+	assert.Equal(t, expectedContent, content)
+}
+
+func TestBitbucketServer_GetFileContentMissingParameter(t *testing.T) {
+	client, cleanup := createBitbucketServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("should not reach the server")
+	})
+	defer cleanup()
+
+	_, err := client.GetFileContent(context.Background(), owner, repo1, branch1, "")
+	assert.Error(t, err)
+}
+
+func TestBitbucketServer_AddPullRequestComment(t *testing.T) {
+	client, cleanup := createBitbucketServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("{}"))
+	})
+	defer cleanup()
+
+	err := client.AddPullRequestComment(context.Background(), owner, repo1, 1, "a comment")
+	assert.NoError(t, err)
+}
+
+func TestBitbucketServer_ParseIncomingWebhookSignatureMismatch(t *testing.T) {
+	client, cleanup := createBitbucketServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("should not reach the server")
+	})
+	defer cleanup()
+
+	headers := http.Header{}
+	headers.Set(bitbucketServerEventHeader, "repo:refs_changed")
+	headers.Set(bitbucketServerSignatureHeader, "sha256=wrong")
+	_, err := client.ParseIncomingWebhook(context.Background(), headers, []byte("{}"), "secret")
+	assert.IsType(t, &WebhookSignatureMismatchError{}, err)
+}
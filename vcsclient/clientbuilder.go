@@ -0,0 +1,70 @@
+package vcsclient
+
+import (
+	"fmt"
+
+	"github.com/jfrog/froggit-go/vcsutils"
+)
+
+// ClientBuilder builds a VcsClient for a specific provider from the supplied connection details.
+type ClientBuilder struct {
+	vcsProvider vcsutils.VcsProvider
+	vcsInfo     VcsInfo
+}
+
+// NewClientBuilder returns a ClientBuilder for the given VCS provider.
+func NewClientBuilder(vcsProvider vcsutils.VcsProvider) *ClientBuilder {
+	return &ClientBuilder{vcsProvider: vcsProvider}
+}
+
+// ApiEndpoint sets the API endpoint of the VCS provider. Optional for github.com and gitea.com.
+func (builder *ClientBuilder) ApiEndpoint(apiEndpoint string) *ClientBuilder {
+	builder.vcsInfo.ApiEndpoint = apiEndpoint
+	return builder
+}
+
+// Username sets the username used for basic-auth requests.
+func (builder *ClientBuilder) Username(username string) *ClientBuilder {
+	builder.vcsInfo.Username = username
+	return builder
+}
+
+// Token sets the access token used to authenticate requests.
+func (builder *ClientBuilder) Token(token string) *ClientBuilder {
+	builder.vcsInfo.Token = token
+	return builder
+}
+
+// OAuth2ClientID sets the OAuth2 client id used by the Login/Authenticate flow.
+func (builder *ClientBuilder) OAuth2ClientID(clientID string) *ClientBuilder {
+	builder.vcsInfo.OAuth2ClientID = clientID
+	return builder
+}
+
+// OAuth2Secret sets the OAuth2 client secret used by the Login/Authenticate flow.
+func (builder *ClientBuilder) OAuth2Secret(secret string) *ClientBuilder {
+	builder.vcsInfo.OAuth2Secret = secret
+	return builder
+}
+
+// OAuth2RedirectURL sets the OAuth2 redirect URL used by the Login/Authenticate flow.
+func (builder *ClientBuilder) OAuth2RedirectURL(redirectURL string) *ClientBuilder {
+	builder.vcsInfo.OAuth2RedirectURL = redirectURL
+	return builder
+}
+
+// Build constructs the VcsClient matching the configured provider.
+func (builder *ClientBuilder) Build() (VcsClient, error) {
+	switch builder.vcsProvider {
+	case vcsutils.GitHub:
+		return NewGitHubClient(builder.vcsInfo)
+	case vcsutils.GitLab:
+		return NewGitLabClient(&builder.vcsInfo)
+	case vcsutils.Gitea:
+		return NewGiteaClient(builder.vcsInfo)
+	case vcsutils.BitbucketServer:
+		return NewBitbucketServerClient(builder.vcsInfo)
+	default:
+		return nil, fmt.Errorf("unsupported vcs provider: %d", builder.vcsProvider)
+	}
+}
@@ -0,0 +1,30 @@
+package vcsclient
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// Netrc returns the machine/login/password triple for user, formatted for a ".netrc" entry
+// ("machine <host> login <user> password <token>"), so a CI clone step can authenticate over HTTPS
+// without embedding the token in the remote URL.
+func (cloneInfo CloneInfo) Netrc(user string) (machine, login, password string) {
+	parsedUrl, err := url.Parse(cloneInfo.HTTP)
+	if err != nil {
+		return "", user, cloneInfo.Token
+	}
+	return parsedUrl.Host, user, cloneInfo.Token
+}
+
+// CloneCommand returns a "git clone" command using the HTTPS URL with the token embedded as Basic-auth
+// credentials, ready to be run from a CI pipeline.
+func (cloneInfo CloneInfo) CloneCommand() (string, error) {
+	parsedUrl, err := url.Parse(cloneInfo.HTTP)
+	if err != nil {
+		return "", err
+	}
+	if cloneInfo.Token != "" {
+		parsedUrl.User = url.UserPassword("token", cloneInfo.Token)
+	}
+	return fmt.Sprintf("git clone %s", parsedUrl.String()), nil
+}
@@ -0,0 +1,9 @@
+package vcsclient
+
+// CommentInfo holds the data of a single comment posted on a pull request / merge request.
+type CommentInfo struct {
+	ID      int64
+	Content string
+	Created int64
+	Author  string
+}
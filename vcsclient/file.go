@@ -0,0 +1,8 @@
+package vcsclient
+
+// FileEntry holds the data of a single file or directory entry, as returned by ListDirectory.
+type FileEntry struct {
+	Name string
+	Path string
+	Type string
+}
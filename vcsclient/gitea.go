@@ -0,0 +1,562 @@
+package vcsclient
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"golang.org/x/oauth2"
+)
+
+const (
+	giteaEventHeader     = "X-Gitea-Event"
+	giteaSignatureHeader = "X-Gitea-Signature"
+)
+
+var giteaOAuthScopes = []string{"repo"}
+
+type GiteaClient struct {
+	vcsInfo VcsInfo
+}
+
+func NewGiteaClient(vcsInfo VcsInfo) (*GiteaClient, error) {
+	return &GiteaClient{vcsInfo: vcsInfo}, nil
+}
+
+func (client *GiteaClient) giteaApiEndpoint() string {
+	if client.vcsInfo.ApiEndpoint == "" {
+		return "https://gitea.com"
+	}
+	return client.vcsInfo.ApiEndpoint
+}
+
+func (client *GiteaClient) buildGiteaClient() (*gitea.Client, error) {
+	return gitea.NewClient(client.giteaApiEndpoint(), gitea.SetToken(client.vcsInfo.Token))
+}
+
+func (client *GiteaClient) TestConnection(ctx context.Context) error {
+	giteaClient, err := client.buildGiteaClient()
+	if err != nil {
+		return err
+	}
+	_, _, err = giteaClient.GetMyUserInfo()
+	return err
+}
+
+func (client *GiteaClient) AddSshKeyToRepository(ctx context.Context, owner, repository, keyName, publicKey string,
+	permission Permission) error {
+	err := validateParametersNotBlank(map[string]string{
+		"owner":      owner,
+		"repository": repository,
+		"key name":   keyName,
+		"public key": publicKey,
+	})
+	if err != nil {
+		return err
+	}
+	giteaClient, err := client.buildGiteaClient()
+	if err != nil {
+		return err
+	}
+	_, _, err = giteaClient.AddDeployKey(owner, repository, gitea.CreateKeyOption{
+		Title:    keyName,
+		Key:      publicKey,
+		ReadOnly: permission != ReadWrite,
+	})
+	return err
+}
+
+func (client *GiteaClient) ListRepositories(ctx context.Context) (map[string][]string, error) {
+	giteaClient, err := client.buildGiteaClient()
+	if err != nil {
+		return nil, err
+	}
+	results := make(map[string][]string)
+	for page := 1; ; page++ {
+		repos, response, err := giteaClient.ListMyRepos(gitea.ListReposOptions{
+			ListOptions: gitea.ListOptions{Page: page},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, repo := range repos {
+			results[repo.Owner.UserName] = append(results[repo.Owner.UserName], repo.Name)
+		}
+		if page >= response.LastPage {
+			break
+		}
+	}
+	return results, nil
+}
+
+func (client *GiteaClient) ListBranches(ctx context.Context, owner, repository string) ([]string, error) {
+	giteaClient, err := client.buildGiteaClient()
+	if err != nil {
+		return nil, err
+	}
+	branches, _, err := giteaClient.ListRepoBranches(owner, repository, gitea.ListRepoBranchesOptions{})
+	if err != nil {
+		return nil, err
+	}
+	results := make([]string, 0, len(branches))
+	for _, branch := range branches {
+		results = append(results, branch.Name)
+	}
+	return results, nil
+}
+
+func (client *GiteaClient) CreateWebhook(ctx context.Context, owner, repository, _, payloadUrl string,
+	webhookEvents ...vcsutils.WebhookEvent) (string, string, error) {
+	giteaClient, err := client.buildGiteaClient()
+	if err != nil {
+		return "", "", err
+	}
+	token := vcsutils.CreateToken()
+	hook, _, err := giteaClient.CreateRepoHook(owner, repository, createGiteaHookOption(token, payloadUrl, webhookEvents...))
+	if err != nil {
+		return "", "", err
+	}
+	return strconv.FormatInt(hook.ID, 10), token, nil
+}
+
+func (client *GiteaClient) UpdateWebhook(ctx context.Context, owner, repository, _, payloadUrl, token,
+	webhookId string, webhookEvents ...vcsutils.WebhookEvent) error {
+	giteaClient, err := client.buildGiteaClient()
+	if err != nil {
+		return err
+	}
+	webhookIdInt64, err := strconv.ParseInt(webhookId, 10, 64)
+	if err != nil {
+		return err
+	}
+	option := createGiteaHookOption(token, payloadUrl, webhookEvents...)
+	_, err = giteaClient.EditRepoHook(owner, repository, webhookIdInt64, gitea.EditHookOption{
+		Config: option.Config,
+		Events: option.Events,
+		Active: option.Active,
+	})
+	return err
+}
+
+func (client *GiteaClient) DeleteWebhook(ctx context.Context, owner, repository, webhookId string) error {
+	giteaClient, err := client.buildGiteaClient()
+	if err != nil {
+		return err
+	}
+	webhookIdInt64, err := strconv.ParseInt(webhookId, 10, 64)
+	if err != nil {
+		return err
+	}
+	_, err = giteaClient.DeleteRepoHook(owner, repository, webhookIdInt64)
+	return err
+}
+
+func (client *GiteaClient) SetCommitStatus(ctx context.Context, commitStatus CommitStatus, owner, repository, ref,
+	title, description, detailsUrl string) error {
+	giteaClient, err := client.buildGiteaClient()
+	if err != nil {
+		return err
+	}
+	_, _, err = giteaClient.CreateStatus(owner, repository, ref, gitea.CreateStatusOption{
+		State:       getGiteaCommitState(commitStatus),
+		TargetURL:   detailsUrl,
+		Description: description,
+		Context:     title,
+	})
+	return err
+}
+
+func (client *GiteaClient) DownloadRepository(ctx context.Context, owner, repository, branch, localPath string) error {
+	giteaClient, err := client.buildGiteaClient()
+	if err != nil {
+		return err
+	}
+	archiveUrl := fmt.Sprintf("%s/%s/%s/archive/%s.tar.gz", client.giteaApiEndpoint(), owner, repository, branch)
+	req, err := http.NewRequest(http.MethodGet, archiveUrl, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "token "+client.vcsInfo.Token)
+	resp, err := giteaClient.Client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	return vcsutils.Untar(localPath, resp.Body, true)
+}
+
+func (client *GiteaClient) CreatePullRequest(ctx context.Context, owner, repository, sourceBranch, targetBranch,
+	title, description string) error {
+	giteaClient, err := client.buildGiteaClient()
+	if err != nil {
+		return err
+	}
+	_, _, err = giteaClient.CreatePullRequest(owner, repository, gitea.CreatePullRequestOption{
+		Head:  sourceBranch,
+		Base:  targetBranch,
+		Title: title,
+		Body:  description,
+	})
+	return err
+}
+
+func (client *GiteaClient) GetLatestCommit(ctx context.Context, owner, repository, branch string) (CommitInfo, error) {
+	err := validateParametersNotBlank(map[string]string{"owner": owner, "repository": repository, "branch": branch})
+	if err != nil {
+		return CommitInfo{}, err
+	}
+	giteaClient, err := client.buildGiteaClient()
+	if err != nil {
+		return CommitInfo{}, err
+	}
+	commits, _, err := giteaClient.ListRepoCommits(owner, repository, gitea.ListCommitOptions{
+		SHA:         branch,
+		ListOptions: gitea.ListOptions{Page: 1, PageSize: 1},
+	})
+	if err != nil {
+		return CommitInfo{}, err
+	}
+	if len(commits) > 0 {
+		return mapGiteaCommitToCommitInfo(commits[0]), nil
+	}
+	return CommitInfo{}, nil
+}
+
+func (client *GiteaClient) GetRepositoryInfo(ctx context.Context, owner, repository string) (RepositoryInfo, error) {
+	err := validateParametersNotBlank(map[string]string{"owner": owner, "repository": repository})
+	if err != nil {
+		return RepositoryInfo{}, err
+	}
+	giteaClient, err := client.buildGiteaClient()
+	if err != nil {
+		return RepositoryInfo{}, err
+	}
+	repo, _, err := giteaClient.GetRepo(owner, repository)
+	if err != nil {
+		return RepositoryInfo{}, err
+	}
+	return RepositoryInfo{CloneInfo: CloneInfo{
+		HTTP:  repo.CloneURL,
+		SSH:   repo.SSHURL,
+		Token: client.vcsInfo.Token,
+	}}, nil
+}
+
+func (client *GiteaClient) GetCommitBySha(ctx context.Context, owner, repository, sha string) (CommitInfo, error) {
+	err := validateParametersNotBlank(map[string]string{"owner": owner, "repository": repository, "sha": sha})
+	if err != nil {
+		return CommitInfo{}, err
+	}
+	giteaClient, err := client.buildGiteaClient()
+	if err != nil {
+		return CommitInfo{}, err
+	}
+	commit, _, err := giteaClient.GetSingleCommit(owner, repository, sha)
+	if err != nil {
+		return CommitInfo{}, err
+	}
+	return mapGiteaCommitToCommitInfo(commit), nil
+}
+
+func (client *GiteaClient) AddPullRequestComment(ctx context.Context, owner, repository string, prID int,
+	content string) error {
+	giteaClient, err := client.buildGiteaClient()
+	if err != nil {
+		return err
+	}
+	_, _, err = giteaClient.CreateIssueComment(owner, repository, int64(prID), gitea.CreateIssueCommentOption{
+		Body: content,
+	})
+	return err
+}
+
+func (client *GiteaClient) ListPullRequestComments(ctx context.Context, owner, repository string,
+	prID int) ([]CommentInfo, error) {
+	giteaClient, err := client.buildGiteaClient()
+	if err != nil {
+		return nil, err
+	}
+	comments, _, err := giteaClient.ListIssueComments(owner, repository, int64(prID), gitea.ListIssueCommentOptions{})
+	if err != nil {
+		return nil, err
+	}
+	results := make([]CommentInfo, len(comments))
+	for i, comment := range comments {
+		results[i] = mapGiteaCommentToCommentInfo(comment)
+	}
+	return results, nil
+}
+
+func (client *GiteaClient) EditPullRequestComment(ctx context.Context, owner, repository string, commentID int64,
+	content string) error {
+	giteaClient, err := client.buildGiteaClient()
+	if err != nil {
+		return err
+	}
+	_, _, err = giteaClient.EditIssueComment(owner, repository, commentID, gitea.EditIssueCommentOption{
+		Body: content,
+	})
+	return err
+}
+
+func (client *GiteaClient) DeletePullRequestComment(ctx context.Context, owner, repository string, commentID int64) error {
+	giteaClient, err := client.buildGiteaClient()
+	if err != nil {
+		return err
+	}
+	_, err = giteaClient.DeleteIssueComment(owner, repository, commentID)
+	return err
+}
+
+func mapGiteaCommentToCommentInfo(comment *gitea.Comment) CommentInfo {
+	return CommentInfo{
+		ID:      comment.ID,
+		Content: comment.Body,
+		Created: comment.Created.UTC().Unix(),
+		Author:  comment.Poster.UserName,
+	}
+}
+
+func (client *GiteaClient) GetFileContent(ctx context.Context, owner, repository, ref, path string) ([]byte, error) {
+	err := validateParametersNotBlank(map[string]string{"owner": owner, "repository": repository, "path": path})
+	if err != nil {
+		return nil, err
+	}
+	giteaClient, err := client.buildGiteaClient()
+	if err != nil {
+		return nil, err
+	}
+	content, _, err := giteaClient.GetFile(owner, repository, ref, path)
+	if err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+func (client *GiteaClient) ListDirectory(ctx context.Context, owner, repository, ref, path string) ([]FileEntry, error) {
+	err := validateParametersNotBlank(map[string]string{"owner": owner, "repository": repository})
+	if err != nil {
+		return nil, err
+	}
+	giteaClient, err := client.buildGiteaClient()
+	if err != nil {
+		return nil, err
+	}
+	entries, _, err := giteaClient.ListContents(owner, repository, ref, path)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]FileEntry, len(entries))
+	for i, entry := range entries {
+		results[i] = FileEntry{Name: entry.Name, Path: entry.Path, Type: entry.Type}
+	}
+	return results, nil
+}
+
+func (client *GiteaClient) giteaOAuthConfig() *oauth2.Config {
+	baseUrl := strings.TrimSuffix(client.giteaApiEndpoint(), "/")
+	return &oauth2.Config{
+		ClientID:     client.vcsInfo.OAuth2ClientID,
+		ClientSecret: client.vcsInfo.OAuth2Secret,
+		RedirectURL:  client.vcsInfo.OAuth2RedirectURL,
+		Scopes:       giteaOAuthScopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  baseUrl + "/login/oauth/authorize",
+			TokenURL: baseUrl + "/login/oauth/access_token",
+		},
+	}
+}
+
+func (client *GiteaClient) Login(ctx context.Context, w http.ResponseWriter, r *http.Request) (string, error) {
+	state, err := generateOAuthState()
+	if err != nil {
+		return "", err
+	}
+	setOAuthStateCookie(w, state)
+	http.Redirect(w, r, client.giteaOAuthConfig().AuthCodeURL(state), http.StatusFound)
+	return state, nil
+}
+
+func (client *GiteaClient) Authenticate(ctx context.Context, w http.ResponseWriter, r *http.Request,
+	state string) (UserInfo, error) {
+	if err := validateOAuthState(r, state); err != nil {
+		return UserInfo{}, err
+	}
+	config := client.giteaOAuthConfig()
+	token, err := config.Exchange(ctx, r.URL.Query().Get("code"))
+	if err != nil {
+		return UserInfo{}, err
+	}
+	giteaClient, err := gitea.NewClient(client.giteaApiEndpoint(), gitea.SetToken(token.AccessToken))
+	if err != nil {
+		return UserInfo{}, err
+	}
+	user, _, err := giteaClient.GetMyUserInfo()
+	if err != nil {
+		return UserInfo{}, err
+	}
+	return UserInfo{Login: user.UserName, Email: user.Email, Token: token.AccessToken}, nil
+}
+
+func (client *GiteaClient) GetRepositoryPermissions(ctx context.Context, owner, repository,
+	username string) (RepoPermissions, error) {
+	err := validateParametersNotBlank(map[string]string{"owner": owner, "repository": repository, "username": username})
+	if err != nil {
+		return RepoPermissions{}, err
+	}
+	giteaClient, err := client.buildGiteaClient()
+	if err != nil {
+		return RepoPermissions{}, err
+	}
+	repo, _, err := giteaClient.GetRepo(owner, repository)
+	if err != nil {
+		return RepoPermissions{}, err
+	}
+	if repo.Permissions == nil {
+		return RepoPermissions{}, nil
+	}
+	return RepoPermissions{
+		Admin: repo.Permissions.Admin,
+		Push:  repo.Permissions.Push,
+		Pull:  repo.Permissions.Pull,
+	}, nil
+}
+
+func (client *GiteaClient) ParseIncomingWebhook(ctx context.Context, headers http.Header, body []byte,
+	secretToken string) (*WebhookInfo, error) {
+	if err := verifyGiteaWebhookSignature(headers.Get(giteaSignatureHeader), body, secretToken); err != nil {
+		return nil, err
+	}
+	switch headers.Get(giteaEventHeader) {
+	case "push":
+		return parseGiteaPushWebhook(body)
+	case "pull_request":
+		return parseGiteaPullRequestWebhook(body)
+	default:
+		return nil, fmt.Errorf("unsupported gitea webhook event: %s", headers.Get(giteaEventHeader))
+	}
+}
+
+func verifyGiteaWebhookSignature(signatureHeader string, body []byte, secretToken string) error {
+	mac := hmac.New(sha256.New, []byte(secretToken))
+	mac.Write(body)
+	computedSignature := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(signatureHeader), []byte(computedSignature)) {
+		return &WebhookSignatureMismatchError{}
+	}
+	return nil
+}
+
+func parseGiteaPushWebhook(body []byte) (*WebhookInfo, error) {
+	var payload gitea.PushPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	commits := make([]WebhookInfoCommit, len(payload.Commits))
+	for i, commit := range payload.Commits {
+		commits[i] = WebhookInfoCommit{
+			Hash:        commit.ID,
+			Message:     commit.Message,
+			AuthorLogin: commit.Author.UserName,
+			AuthorEmail: commit.Author.Email,
+		}
+	}
+	return &WebhookInfo{
+		TargetRepositoryOwner: payload.Repo.Owner.UserName,
+		TargetRepositoryName:  payload.Repo.Name,
+		Event:                 vcsutils.Push,
+		Branch:                strings.TrimPrefix(payload.Ref, "refs/heads/"),
+		Commits:               commits,
+	}, nil
+}
+
+func parseGiteaPullRequestWebhook(body []byte) (*WebhookInfo, error) {
+	var payload gitea.PullRequestPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	event := vcsutils.PrEdited
+	switch payload.Action {
+	case gitea.HookIssueOpened:
+		event = vcsutils.PrCreated
+	case gitea.HookIssueEdited, gitea.HookIssueSynchronized:
+		event = vcsutils.PrEdited
+	default:
+		return nil, fmt.Errorf("unsupported gitea pull request action: %s", payload.Action)
+	}
+	return &WebhookInfo{
+		TargetRepositoryOwner: payload.Repository.Owner.UserName,
+		TargetRepositoryName:  payload.Repository.Name,
+		Event:                 event,
+		SourceBranch:          payload.PullRequest.Head.Ref,
+		TargetBranch:          payload.PullRequest.Base.Ref,
+		PullRequestId:         int(payload.PullRequest.Index),
+		PullRequestTitle:      payload.PullRequest.Title,
+		PullRequestBody:       payload.PullRequest.Body,
+	}, nil
+}
+
+func createGiteaHookOption(token, payloadUrl string, webhookEvents ...vcsutils.WebhookEvent) gitea.CreateHookOption {
+	return gitea.CreateHookOption{
+		Type: gitea.HookTypeGitea,
+		Config: map[string]string{
+			"url":          payloadUrl,
+			"content_type": "json",
+			"secret":       token,
+		},
+		Events: getGiteaWebhookEvents(webhookEvents...),
+		Active: true,
+	}
+}
+
+// Get varargs of webhook events and return a slice of Gitea webhook events
+func getGiteaWebhookEvents(webhookEvents ...vcsutils.WebhookEvent) []string {
+	events := make([]string, 0, len(webhookEvents))
+	for _, event := range webhookEvents {
+		switch event {
+		case vcsutils.PrCreated, vcsutils.PrEdited:
+			events = append(events, "pull_request")
+		case vcsutils.Push:
+			events = append(events, "push")
+		}
+	}
+	return events
+}
+
+func getGiteaCommitState(commitState CommitStatus) gitea.StatusState {
+	switch commitState {
+	case Pass:
+		return gitea.StatusSuccess
+	case Fail:
+		return gitea.StatusFailure
+	case Error:
+		return gitea.StatusError
+	case InProgress:
+		return gitea.StatusPending
+	}
+	return ""
+}
+
+func mapGiteaCommitToCommitInfo(commit *gitea.Commit) CommitInfo {
+	parents := make([]string, len(commit.Parents))
+	for i, c := range commit.Parents {
+		parents[i] = c.SHA
+	}
+	return CommitInfo{
+		Hash:          commit.SHA,
+		AuthorName:    commit.RepoCommit.Author.Name,
+		CommitterName: commit.RepoCommit.Committer.Name,
+		Url:           commit.URL,
+		Timestamp:     commit.Created.UTC().Unix(),
+		Message:       commit.RepoCommit.Message,
+		ParentHashes:  parents,
+	}
+}
@@ -0,0 +1,59 @@
+package vcsclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func createGiteaClient(t *testing.T, handler http.HandlerFunc) (*GiteaClient, func()) {
+	server := httptest.NewServer(handler)
+	client, err := NewGiteaClient(VcsInfo{ApiEndpoint: server.URL, Token: token})
+	assert.NoError(t, err)
+	return client, server.Close
+}
+
+func TestGitea_BuiltThroughClientBuilder(t *testing.T) {
+	client, err := NewClientBuilder(vcsutils.Gitea).ApiEndpoint("https://gitea.example.com").Token(token).Build()
+	assert.NoError(t, err)
+	assert.IsType(t, &GiteaClient{}, client)
+}
+
+func TestGitea_ApiEndpointDefaultsToGiteaCom(t *testing.T) {
+	client, err := NewGiteaClient(VcsInfo{})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://gitea.com", client.giteaApiEndpoint())
+}
+
+func TestGitea_ApiEndpointRespectsOverride(t *testing.T) {
+	client, err := NewGiteaClient(VcsInfo{ApiEndpoint: "https://gitea.example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://gitea.example.com", client.giteaApiEndpoint())
+}
+
+func TestGitea_GetFileContentMissingParameter(t *testing.T) {
+	client, cleanup := createGiteaClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("should not reach the server")
+	})
+	defer cleanup()
+
+	_, err := client.GetFileContent(context.Background(), owner, repo1, branch1, "")
+	assert.Error(t, err)
+}
+
+func TestGitea_ParseIncomingWebhookSignatureMismatch(t *testing.T) {
+	client, cleanup := createGiteaClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("should not reach the server")
+	})
+	defer cleanup()
+
+	headers := http.Header{}
+	headers.Set(giteaEventHeader, "push")
+	headers.Set(giteaSignatureHeader, "wrong")
+	_, err := client.ParseIncomingWebhook(context.Background(), headers, []byte("{}"), "secret")
+	assert.IsType(t, &WebhookSignatureMismatchError{}, err)
+}
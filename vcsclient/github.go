@@ -2,6 +2,11 @@ package vcsclient
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -12,6 +17,15 @@ import (
 	"golang.org/x/oauth2"
 )
 
+const (
+	githubEventHeader     = "X-GitHub-Event"
+	githubSignatureHeader = "X-Hub-Signature-256"
+	githubOAuthAuthUrl    = "https://github.com/login/oauth/authorize"
+	githubOAuthTokenUrl   = "https://github.com/login/oauth/access_token"
+)
+
+var githubOAuthScopes = []string{"repo", "user:email", "admin:repo_hook"}
+
 type GitHubClient struct {
 	vcsInfo VcsInfo
 }
@@ -260,7 +274,37 @@ func (client *GitHubClient) GetRepositoryInfo(ctx context.Context, owner, reposi
 	if err != nil {
 		return RepositoryInfo{}, err
 	}
-	return RepositoryInfo{CloneInfo: CloneInfo{HTTP: repo.GetCloneURL(), SSH: repo.GetSSHURL()}}, nil
+	return RepositoryInfo{CloneInfo: CloneInfo{
+		HTTP:  repo.GetCloneURL(),
+		SSH:   repo.GetSSHURL(),
+		Token: client.vcsInfo.Token,
+	}}, nil
+}
+
+func (client *GitHubClient) GetRepositoryPermissions(ctx context.Context, owner, repository,
+	username string) (RepoPermissions, error) {
+	err := validateParametersNotBlank(map[string]string{"owner": owner, "repository": repository, "username": username})
+	if err != nil {
+		return RepoPermissions{}, err
+	}
+	ghClient, err := client.buildGithubClient(ctx)
+	if err != nil {
+		return RepoPermissions{}, err
+	}
+	permissionLevel, _, err := ghClient.Repositories.GetPermissionLevel(ctx, owner, repository, username)
+	if err != nil {
+		return RepoPermissions{}, err
+	}
+	switch permissionLevel.GetPermission() {
+	case "admin":
+		return RepoPermissions{Admin: true, Push: true, Pull: true}, nil
+	case "write":
+		return RepoPermissions{Push: true, Pull: true}, nil
+	case "read":
+		return RepoPermissions{Pull: true}, nil
+	default:
+		return RepoPermissions{}, nil
+	}
 }
 
 func (client *GitHubClient) GetCommitBySha(ctx context.Context, owner, repository, sha string) (CommitInfo, error) {
@@ -286,6 +330,221 @@ func (client *GitHubClient) GetCommitBySha(ctx context.Context, owner, repositor
 	return mapGitHubCommitToCommitInfo(commit), nil
 }
 
+func (client *GitHubClient) ParseIncomingWebhook(ctx context.Context, headers http.Header, body []byte,
+	secretToken string) (*WebhookInfo, error) {
+	if err := verifyGitHubWebhookSignature(headers.Get(githubSignatureHeader), body, secretToken); err != nil {
+		return nil, err
+	}
+	switch headers.Get(githubEventHeader) {
+	case "push":
+		return parseGitHubPushWebhook(body)
+	case "pull_request":
+		return parseGitHubPullRequestWebhook(body)
+	default:
+		return nil, fmt.Errorf("unsupported github webhook event: %s", headers.Get(githubEventHeader))
+	}
+}
+
+func verifyGitHubWebhookSignature(signatureHeader string, body []byte, secretToken string) error {
+	expectedSignature := strings.TrimPrefix(signatureHeader, "sha256=")
+	mac := hmac.New(sha256.New, []byte(secretToken))
+	mac.Write(body)
+	computedSignature := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expectedSignature), []byte(computedSignature)) {
+		return &WebhookSignatureMismatchError{}
+	}
+	return nil
+}
+
+func parseGitHubPushWebhook(body []byte) (*WebhookInfo, error) {
+	var payload github.PushEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	commits := make([]WebhookInfoCommit, len(payload.Commits))
+	for i, commit := range payload.Commits {
+		commits[i] = WebhookInfoCommit{
+			Hash:        commit.GetSHA(),
+			Message:     commit.GetMessage(),
+			AuthorLogin: commit.GetAuthor().GetLogin(),
+			AuthorEmail: commit.GetAuthor().GetEmail(),
+		}
+	}
+	return &WebhookInfo{
+		TargetRepositoryOwner: payload.GetRepo().GetOwner().GetLogin(),
+		TargetRepositoryName:  payload.GetRepo().GetName(),
+		Event:                 vcsutils.Push,
+		Branch:                strings.TrimPrefix(payload.GetRef(), "refs/heads/"),
+		Commits:               commits,
+	}, nil
+}
+
+func parseGitHubPullRequestWebhook(body []byte) (*WebhookInfo, error) {
+	var payload github.PullRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	event := vcsutils.PrEdited
+	switch payload.GetAction() {
+	case "opened":
+		event = vcsutils.PrCreated
+	case "edited", "synchronize":
+		event = vcsutils.PrEdited
+	default:
+		return nil, fmt.Errorf("unsupported github pull request action: %s", payload.GetAction())
+	}
+	return &WebhookInfo{
+		TargetRepositoryOwner: payload.GetRepo().GetOwner().GetLogin(),
+		TargetRepositoryName:  payload.GetRepo().GetName(),
+		Event:                 event,
+		SourceBranch:          payload.GetPullRequest().GetHead().GetRef(),
+		TargetBranch:          payload.GetPullRequest().GetBase().GetRef(),
+		PullRequestId:         payload.GetPullRequest().GetNumber(),
+		PullRequestTitle:      payload.GetPullRequest().GetTitle(),
+		PullRequestBody:       payload.GetPullRequest().GetBody(),
+	}, nil
+}
+
+func (client *GitHubClient) AddPullRequestComment(ctx context.Context, owner, repository string, prID int,
+	content string) error {
+	ghClient, err := client.buildGithubClient(ctx)
+	if err != nil {
+		return err
+	}
+	_, _, err = ghClient.Issues.CreateComment(ctx, owner, repository, prID, &github.IssueComment{Body: &content})
+	return err
+}
+
+func (client *GitHubClient) ListPullRequestComments(ctx context.Context, owner, repository string,
+	prID int) ([]CommentInfo, error) {
+	ghClient, err := client.buildGithubClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	comments, _, err := ghClient.Issues.ListComments(ctx, owner, repository, prID, nil)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]CommentInfo, len(comments))
+	for i, comment := range comments {
+		results[i] = mapGitHubIssueCommentToCommentInfo(comment)
+	}
+	return results, nil
+}
+
+func (client *GitHubClient) EditPullRequestComment(ctx context.Context, owner, repository string, commentID int64,
+	content string) error {
+	ghClient, err := client.buildGithubClient(ctx)
+	if err != nil {
+		return err
+	}
+	_, _, err = ghClient.Issues.EditComment(ctx, owner, repository, commentID, &github.IssueComment{Body: &content})
+	return err
+}
+
+func (client *GitHubClient) DeletePullRequestComment(ctx context.Context, owner, repository string, commentID int64) error {
+	ghClient, err := client.buildGithubClient(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = ghClient.Issues.DeleteComment(ctx, owner, repository, commentID)
+	return err
+}
+
+func mapGitHubIssueCommentToCommentInfo(comment *github.IssueComment) CommentInfo {
+	return CommentInfo{
+		ID:      comment.GetID(),
+		Content: comment.GetBody(),
+		Created: comment.GetCreatedAt().UTC().Unix(),
+		Author:  comment.GetUser().GetLogin(),
+	}
+}
+
+func (client *GitHubClient) GetFileContent(ctx context.Context, owner, repository, ref, path string) ([]byte, error) {
+	err := validateParametersNotBlank(map[string]string{"owner": owner, "repository": repository, "path": path})
+	if err != nil {
+		return nil, err
+	}
+	ghClient, err := client.buildGithubClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fileContent, _, _, err := ghClient.Repositories.GetContents(ctx, owner, repository, path,
+		&github.RepositoryContentGetOptions{Ref: ref})
+	if err != nil {
+		return nil, err
+	}
+	if fileContent == nil {
+		return nil, fmt.Errorf("%s is a directory, not a file", path)
+	}
+	content, err := fileContent.GetContent()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(content), nil
+}
+
+func (client *GitHubClient) ListDirectory(ctx context.Context, owner, repository, ref, path string) ([]FileEntry, error) {
+	err := validateParametersNotBlank(map[string]string{"owner": owner, "repository": repository})
+	if err != nil {
+		return nil, err
+	}
+	ghClient, err := client.buildGithubClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	_, directoryContent, _, err := ghClient.Repositories.GetContents(ctx, owner, repository, path,
+		&github.RepositoryContentGetOptions{Ref: ref})
+	if err != nil {
+		return nil, err
+	}
+	results := make([]FileEntry, len(directoryContent))
+	for i, entry := range directoryContent {
+		results[i] = FileEntry{Name: entry.GetName(), Path: entry.GetPath(), Type: entry.GetType()}
+	}
+	return results, nil
+}
+
+func (client *GitHubClient) githubOAuthConfig() *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     client.vcsInfo.OAuth2ClientID,
+		ClientSecret: client.vcsInfo.OAuth2Secret,
+		RedirectURL:  client.vcsInfo.OAuth2RedirectURL,
+		Scopes:       githubOAuthScopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  githubOAuthAuthUrl,
+			TokenURL: githubOAuthTokenUrl,
+		},
+	}
+}
+
+func (client *GitHubClient) Login(ctx context.Context, w http.ResponseWriter, r *http.Request) (string, error) {
+	state, err := generateOAuthState()
+	if err != nil {
+		return "", err
+	}
+	setOAuthStateCookie(w, state)
+	http.Redirect(w, r, client.githubOAuthConfig().AuthCodeURL(state), http.StatusFound)
+	return state, nil
+}
+
+func (client *GitHubClient) Authenticate(ctx context.Context, w http.ResponseWriter, r *http.Request,
+	state string) (UserInfo, error) {
+	if err := validateOAuthState(r, state); err != nil {
+		return UserInfo{}, err
+	}
+	token, err := client.githubOAuthConfig().Exchange(ctx, r.URL.Query().Get("code"))
+	if err != nil {
+		return UserInfo{}, err
+	}
+	ghClient := github.NewClient(oauth2.NewClient(ctx, oauth2.StaticTokenSource(token)))
+	user, _, err := ghClient.Users.Get(ctx, "")
+	if err != nil {
+		return UserInfo{}, err
+	}
+	return UserInfo{Login: user.GetLogin(), Email: user.GetEmail(), Token: token.AccessToken}, nil
+}
+
 func createGitHubHook(token, payloadUrl string, webhookEvents ...vcsutils.WebhookEvent) *github.Hook {
 	return &github.Hook{
 		Events: getGitHubWebhookEvents(webhookEvents...),
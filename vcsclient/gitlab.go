@@ -3,14 +3,26 @@ package vcsclient
 import (
 	"bytes"
 	"context"
+	"crypto/subtle"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/jfrog/froggit-go/vcsutils"
 	"github.com/xanzy/go-gitlab"
+	"golang.org/x/oauth2"
+)
+
+const (
+	gitlabEventHeader = "X-Gitlab-Event"
+	gitlabTokenHeader = "X-Gitlab-Token"
+	gitlabOAuthScope  = "api"
 )
 
 type GitLabClient struct {
+	vcsInfo  *VcsInfo
 	glClient *gitlab.Client
 }
 
@@ -27,6 +39,7 @@ func NewGitLabClient(vcsInfo *VcsInfo) (*GitLabClient, error) {
 	}
 
 	return &GitLabClient{
+		vcsInfo:  vcsInfo,
 		glClient: client,
 	}, nil
 }
@@ -166,6 +179,229 @@ func (client *GitLabClient) CreatePullRequest(ctx context.Context, owner, reposi
 	return err
 }
 
+func (client *GitLabClient) ParseIncomingWebhook(ctx context.Context, headers http.Header, body []byte,
+	secretToken string) (*WebhookInfo, error) {
+	if subtle.ConstantTimeCompare([]byte(headers.Get(gitlabTokenHeader)), []byte(secretToken)) != 1 {
+		return nil, &WebhookSignatureMismatchError{}
+	}
+	switch headers.Get(gitlabEventHeader) {
+	case "Push Hook":
+		return parseGitLabPushWebhook(body)
+	case "Merge Request Hook":
+		return parseGitLabMergeRequestWebhook(body)
+	default:
+		return nil, fmt.Errorf("unsupported gitlab webhook event: %s", headers.Get(gitlabEventHeader))
+	}
+}
+
+func parseGitLabPushWebhook(body []byte) (*WebhookInfo, error) {
+	var payload gitlab.PushEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	commits := make([]WebhookInfoCommit, len(payload.Commits))
+	for i, commit := range payload.Commits {
+		commits[i] = WebhookInfoCommit{
+			Hash:        commit.ID,
+			Message:     commit.Message,
+			AuthorLogin: commit.Author.Name,
+			AuthorEmail: commit.Author.Email,
+		}
+	}
+	return &WebhookInfo{
+		TargetRepositoryOwner: payload.Project.Namespace,
+		TargetRepositoryName:  payload.Project.Name,
+		Event:                 vcsutils.Push,
+		Branch:                strings.TrimPrefix(payload.Ref, "refs/heads/"),
+		Commits:               commits,
+	}, nil
+}
+
+func parseGitLabMergeRequestWebhook(body []byte) (*WebhookInfo, error) {
+	var payload gitlab.MergeEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	event := vcsutils.PrEdited
+	switch payload.ObjectAttributes.Action {
+	case "open":
+		event = vcsutils.PrCreated
+	case "update":
+		event = vcsutils.PrEdited
+	default:
+		return nil, fmt.Errorf("unsupported gitlab merge request action: %s", payload.ObjectAttributes.Action)
+	}
+	return &WebhookInfo{
+		TargetRepositoryOwner: payload.Project.Namespace,
+		TargetRepositoryName:  payload.Project.Name,
+		Event:                 event,
+		SourceBranch:          payload.ObjectAttributes.SourceBranch,
+		TargetBranch:          payload.ObjectAttributes.TargetBranch,
+		PullRequestId:         payload.ObjectAttributes.IID,
+		PullRequestTitle:      payload.ObjectAttributes.Title,
+		PullRequestBody:       payload.ObjectAttributes.Description,
+	}, nil
+}
+
+func (client *GitLabClient) AddPullRequestComment(ctx context.Context, owner, repository string, prID int,
+	content string) error {
+	options := &gitlab.CreateMergeRequestNoteOptions{Body: &content}
+	_, _, err := client.glClient.Notes.CreateMergeRequestNote(getProjectId(owner, repository), prID, options,
+		gitlab.WithContext(ctx))
+	return err
+}
+
+func (client *GitLabClient) ListPullRequestComments(ctx context.Context, owner, repository string,
+	prID int) ([]CommentInfo, error) {
+	notes, _, err := client.glClient.Notes.ListMergeRequestNotes(getProjectId(owner, repository), prID, nil,
+		gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	results := make([]CommentInfo, len(notes))
+	for i, note := range notes {
+		results[i] = mapGitLabNoteToCommentInfo(note)
+	}
+	return results, nil
+}
+
+func (client *GitLabClient) EditPullRequestComment(ctx context.Context, owner, repository string, commentID int64,
+	content string) error {
+	options := &gitlab.UpdateMergeRequestNoteOptions{Body: &content}
+	_, _, err := client.glClient.Notes.UpdateMergeRequestNote(getProjectId(owner, repository), int(commentID),
+		options, gitlab.WithContext(ctx))
+	return err
+}
+
+func (client *GitLabClient) DeletePullRequestComment(ctx context.Context, owner, repository string, commentID int64) error {
+	_, err := client.glClient.Notes.DeleteMergeRequestNote(getProjectId(owner, repository), int(commentID),
+		gitlab.WithContext(ctx))
+	return err
+}
+
+func mapGitLabNoteToCommentInfo(note *gitlab.Note) CommentInfo {
+	var created int64
+	if note.CreatedAt != nil {
+		created = note.CreatedAt.UTC().Unix()
+	}
+	return CommentInfo{
+		ID:      int64(note.ID),
+		Content: note.Body,
+		Created: created,
+		Author:  note.Author.Username,
+	}
+}
+
+func (client *GitLabClient) GetFileContent(ctx context.Context, owner, repository, ref, path string) ([]byte, error) {
+	err := validateParametersNotBlank(map[string]string{"owner": owner, "repository": repository, "path": path})
+	if err != nil {
+		return nil, err
+	}
+	options := &gitlab.GetRawFileOptions{Ref: &ref}
+	content, _, err := client.glClient.RepositoryFiles.GetRawFile(getProjectId(owner, repository), path, options,
+		gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+func (client *GitLabClient) ListDirectory(ctx context.Context, owner, repository, ref, path string) ([]FileEntry, error) {
+	options := &gitlab.ListTreeOptions{Path: &path, Ref: &ref}
+	nodes, _, err := client.glClient.Repositories.ListTree(getProjectId(owner, repository), options,
+		gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	results := make([]FileEntry, len(nodes))
+	for i, node := range nodes {
+		results[i] = FileEntry{Name: node.Name, Path: node.Path, Type: node.Type}
+	}
+	return results, nil
+}
+
+func (client *GitLabClient) gitlabOAuthConfig() *oauth2.Config {
+	baseUrl := gitlabBaseUrl(client.vcsInfo.ApiEndpoint)
+	return &oauth2.Config{
+		ClientID:     client.vcsInfo.OAuth2ClientID,
+		ClientSecret: client.vcsInfo.OAuth2Secret,
+		RedirectURL:  client.vcsInfo.OAuth2RedirectURL,
+		Scopes:       []string{gitlabOAuthScope},
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  baseUrl + "/oauth/authorize",
+			TokenURL: baseUrl + "/oauth/token",
+		},
+	}
+}
+
+func gitlabBaseUrl(apiEndpoint string) string {
+	if apiEndpoint == "" {
+		return "https://gitlab.com"
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(apiEndpoint, "/"), "/api/v4")
+}
+
+func (client *GitLabClient) Login(ctx context.Context, w http.ResponseWriter, r *http.Request) (string, error) {
+	state, err := generateOAuthState()
+	if err != nil {
+		return "", err
+	}
+	setOAuthStateCookie(w, state)
+	http.Redirect(w, r, client.gitlabOAuthConfig().AuthCodeURL(state), http.StatusFound)
+	return state, nil
+}
+
+func (client *GitLabClient) Authenticate(ctx context.Context, w http.ResponseWriter, r *http.Request,
+	state string) (UserInfo, error) {
+	if err := validateOAuthState(r, state); err != nil {
+		return UserInfo{}, err
+	}
+	config := client.gitlabOAuthConfig()
+	token, err := config.Exchange(ctx, r.URL.Query().Get("code"))
+	if err != nil {
+		return UserInfo{}, err
+	}
+	resp, err := config.Client(ctx, token).Get(gitlabBaseUrl(client.vcsInfo.ApiEndpoint) + "/api/v4/user")
+	if err != nil {
+		return UserInfo{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var user struct {
+		Username string `json:"username"`
+		Email    string `json:"email"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return UserInfo{}, err
+	}
+	return UserInfo{Login: user.Username, Email: user.Email, Token: token.AccessToken}, nil
+}
+
+func (client *GitLabClient) GetRepositoryPermissions(ctx context.Context, owner, repository,
+	username string) (RepoPermissions, error) {
+	users, _, err := client.glClient.Users.ListUsers(&gitlab.ListUsersOptions{Username: &username},
+		gitlab.WithContext(ctx))
+	if err != nil {
+		return RepoPermissions{}, err
+	}
+	if len(users) == 0 {
+		return RepoPermissions{}, fmt.Errorf("gitlab user not found: %s", username)
+	}
+	member, _, err := client.glClient.ProjectMembers.GetInheritedProjectMember(getProjectId(owner, repository),
+		users[0].ID, gitlab.WithContext(ctx))
+	if err != nil {
+		return RepoPermissions{}, err
+	}
+	return mapGitLabAccessLevelToPermissions(member.AccessLevel), nil
+}
+
+func mapGitLabAccessLevelToPermissions(level gitlab.AccessLevelValue) RepoPermissions {
+	return RepoPermissions{
+		Pull:  level > 0,
+		Push:  level >= 40,
+		Admin: level >= 50,
+	}
+}
+
 func getProjectId(owner, project string) string {
 	return fmt.Sprintf("%s/%s", owner, project)
 }
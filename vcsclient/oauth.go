@@ -0,0 +1,52 @@
+package vcsclient
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+)
+
+// UserInfo holds the data of a VCS user that completed the OAuth2 login flow.
+type UserInfo struct {
+	Login string
+	Email string
+	Token string
+}
+
+const oauthStateCookieName = "froggit-go-oauth-state"
+
+// generateOAuthState returns a random, URL-safe string to be used as the OAuth2 "state" parameter.
+func generateOAuthState() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(buf), nil
+}
+
+func setOAuthStateCookie(w http.ResponseWriter, state string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     oauthStateCookieName,
+		Value:    state,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// validateOAuthState makes sure the state the provider echoed back on the callback request's query string
+// matches both the one stored in the cookie set by Login and the state the caller expects, protecting the
+// flow against CSRF.
+func validateOAuthState(r *http.Request, expectedState string) error {
+	cookie, err := r.Cookie(oauthStateCookieName)
+	if err != nil {
+		return err
+	}
+	returnedState := r.URL.Query().Get("state")
+	if returnedState == "" || cookie.Value != returnedState || returnedState != expectedState {
+		return fmt.Errorf("oauth2 state mismatch")
+	}
+	return nil
+}
@@ -0,0 +1,8 @@
+package vcsclient
+
+// RepoPermissions describes what a user (or the token itself) is allowed to do on a repository.
+type RepoPermissions struct {
+	Admin bool
+	Push  bool
+	Pull  bool
+}
@@ -0,0 +1,138 @@
+package vcsclient
+
+import (
+	"context"
+
+	"net/http"
+
+	"github.com/jfrog/froggit-go/vcsutils"
+)
+
+// VcsClient is the unified interface implemented by every supported VCS provider.
+type VcsClient interface {
+	// TestConnection checks connectivity and authentication against the provider.
+	TestConnection(ctx context.Context) error
+
+	// AddSshKeyToRepository adds a public ssh key to a repository.
+	AddSshKeyToRepository(ctx context.Context, owner, repository, keyName, publicKey string, permission Permission) error
+
+	// ListRepositories returns a map between all accessible owners to their list of repositories.
+	ListRepositories(ctx context.Context) (map[string][]string, error)
+
+	// ListBranches lists the branches under a specific repository. The branches are fetched with a single
+	// request, so where the provider paginates the result only its first page is returned.
+	ListBranches(ctx context.Context, owner, repository string) ([]string, error)
+
+	// CreateWebhook creates a webhook subscribing to the given events and returns its id and token.
+	CreateWebhook(ctx context.Context, owner, repository, branch, payloadUrl string,
+		webhookEvents ...vcsutils.WebhookEvent) (id, token string, err error)
+
+	// UpdateWebhook updates an existing webhook.
+	UpdateWebhook(ctx context.Context, owner, repository, branch, payloadUrl, token, webhookId string,
+		webhookEvents ...vcsutils.WebhookEvent) error
+
+	// DeleteWebhook deletes a webhook.
+	DeleteWebhook(ctx context.Context, owner, repository, webhookId string) error
+
+	// SetCommitStatus sets commit status on a ref.
+	SetCommitStatus(ctx context.Context, commitStatus CommitStatus, owner, repository, ref, title, description,
+		detailsUrl string) error
+
+	// DownloadRepository downloads and extracts a repository at a given branch into localPath.
+	DownloadRepository(ctx context.Context, owner, repository, branch, localPath string) error
+
+	// CreatePullRequest creates a pull request between sourceBranch and targetBranch.
+	CreatePullRequest(ctx context.Context, owner, repository, sourceBranch, targetBranch, title,
+		description string) error
+
+	// GetLatestCommit returns the latest commit of a branch.
+	GetLatestCommit(ctx context.Context, owner, repository, branch string) (CommitInfo, error)
+
+	// GetRepositoryInfo returns general repository information, such as clone URLs.
+	GetRepositoryInfo(ctx context.Context, owner, repository string) (RepositoryInfo, error)
+
+	// GetCommitBySha returns a specific commit by its sha.
+	GetCommitBySha(ctx context.Context, owner, repository, sha string) (CommitInfo, error)
+
+	// AddPullRequestComment adds a new comment on an open pull request.
+	AddPullRequestComment(ctx context.Context, owner, repository string, prID int, content string) error
+
+	// ListPullRequestComments lists the comments on an open pull request. The comments are fetched with a
+	// single request, so where the provider paginates the result only its first page is returned.
+	ListPullRequestComments(ctx context.Context, owner, repository string, prID int) ([]CommentInfo, error)
+
+	// EditPullRequestComment edits the content of an existing pull request comment.
+	EditPullRequestComment(ctx context.Context, owner, repository string, commentID int64, content string) error
+
+	// DeletePullRequestComment deletes an existing pull request comment.
+	DeletePullRequestComment(ctx context.Context, owner, repository string, commentID int64) error
+
+	// GetFileContent fetches the content of a single file at ref, without downloading the whole repository.
+	GetFileContent(ctx context.Context, owner, repository, ref, path string) ([]byte, error)
+
+	// ListDirectory lists the entries of a directory at ref.
+	ListDirectory(ctx context.Context, owner, repository, ref, path string) ([]FileEntry, error)
+
+	// Login starts the OAuth2 authorization-code flow by redirecting to the provider's consent page and
+	// returns the state that was stored in the CSRF cookie.
+	Login(ctx context.Context, w http.ResponseWriter, r *http.Request) (string, error)
+
+	// Authenticate completes the OAuth2 flow started by Login, validating the callback's state against
+	// the CSRF cookie before exchanging the authorization code for the authenticated user's details.
+	Authenticate(ctx context.Context, w http.ResponseWriter, r *http.Request, state string) (UserInfo, error)
+
+	// GetRepositoryPermissions returns the permissions a specific user has on a repository.
+	GetRepositoryPermissions(ctx context.Context, owner, repository, username string) (RepoPermissions, error)
+}
+
+// VcsInfo holds the connection details used to instantiate a VcsClient.
+type VcsInfo struct {
+	ApiEndpoint       string
+	Username          string
+	Token             string
+	OAuth2ClientID    string
+	OAuth2Secret      string
+	OAuth2RedirectURL string
+}
+
+// Permission represents the access level granted to a deploy key.
+type Permission int
+
+const (
+	ReadOnly Permission = iota
+	ReadWrite
+)
+
+// CommitStatus represents the result of a CI check reported on a commit.
+type CommitStatus int
+
+const (
+	Pass CommitStatus = iota
+	Fail
+	Error
+	InProgress
+)
+
+// CommitInfo holds the data of a single commit.
+type CommitInfo struct {
+	Hash          string
+	AuthorName    string
+	CommitterName string
+	Url           string
+	Timestamp     int64
+	Message       string
+	ParentHashes  []string
+}
+
+// RepositoryInfo holds general information about a repository.
+type RepositoryInfo struct {
+	CloneInfo CloneInfo
+}
+
+// CloneInfo holds the HTTP and SSH clone URLs of a repository, together with the token needed to
+// authenticate an automated clone (e.g. from a CI pipeline).
+type CloneInfo struct {
+	HTTP  string
+	SSH   string
+	Token string
+}
@@ -0,0 +1,46 @@
+package vcsclient
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/jfrog/froggit-go/vcsutils"
+)
+
+// WebhookParser is implemented by VcsClient implementations that are able to authenticate and parse
+// an incoming webhook call into a provider-agnostic WebhookInfo.
+type WebhookParser interface {
+	// ParseIncomingWebhook authenticates the incoming request against secretToken and, if valid, parses
+	// its body into a WebhookInfo. Returns a *WebhookSignatureMismatchError if authentication fails.
+	ParseIncomingWebhook(ctx context.Context, headers http.Header, body []byte, secretToken string) (*WebhookInfo, error)
+}
+
+// WebhookInfo is the provider-agnostic representation of an event delivered by an incoming webhook call.
+type WebhookInfo struct {
+	TargetRepositoryOwner string
+	TargetRepositoryName  string
+	Event                 vcsutils.WebhookEvent
+	Branch                string
+	SourceBranch          string
+	TargetBranch          string
+	Commits               []WebhookInfoCommit
+	PullRequestId         int
+	PullRequestTitle      string
+	PullRequestBody       string
+}
+
+// WebhookInfoCommit holds the data of a single commit included in an incoming webhook payload.
+type WebhookInfoCommit struct {
+	Hash        string
+	Message     string
+	AuthorLogin string
+	AuthorEmail string
+}
+
+// WebhookSignatureMismatchError is returned by ParseIncomingWebhook when the incoming request fails to
+// authenticate against the secret token that was used to create the webhook.
+type WebhookSignatureMismatchError struct{}
+
+func (e *WebhookSignatureMismatchError) Error() string {
+	return "webhook signature mismatch"
+}
@@ -0,0 +1,11 @@
+package vcsutils
+
+// VcsProvider is an enum of the supported VCS providers.
+type VcsProvider int
+
+const (
+	GitHub VcsProvider = iota
+	GitLab
+	Gitea
+	BitbucketServer
+)